@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/contentsquare/lakehouse-cli/helpers"
+	"github.com/contentsquare/lakehouse-cli/metrics"
+	"github.com/contentsquare/lakehouse-cli/providers"
+	"github.com/contentsquare/lakehouse-cli/slack"
+	"github.com/spf13/cobra"
+)
+
+// StaleTable describes a candidate for deletion, combining the query_log
+// lookback with system.tables metadata.
+type StaleTable struct {
+	Database   string `json:"database"`
+	Table      string `json:"table"`
+	LastUsed   string `json:"last_used"`
+	CreateTime string `json:"create_time"`
+	TotalBytes int64  `json:"total_bytes"`
+	Comment    string `json:"comment"`
+}
+
+// Variables to store flag values.
+var (
+	lcURL           string
+	lcUser          string
+	lcPassword      string
+	lcRegion        string
+	lcEnv           string
+	lcCloudProvider string
+	lcSlackToken    string
+	lcMinUnusedDays int
+	lcDryRun        bool
+	lcExcludeRegex  string
+	lcProtectLabel  string
+)
+
+// Define the lakehouseCleanupCmd command with its usage, short description, and execution function.
+var lakehouseCleanupCmd = &cobra.Command{
+	Use:   "lakehouse-cleanup",
+	Short: "Drops Lakehouse tables that have been unused for a configurable number of days",
+	RunE:  runLakeHouseCleanupCmd,
+}
+
+// init function to initialize the command and its flags.
+func init() {
+	rootCmd.AddCommand(lakehouseCleanupCmd)
+
+	// Define persistent flags for the command
+	lakehouseCleanupCmd.PersistentFlags().StringVar(&lcURL,
+		"url",
+		"",
+		"URL of the ClickHouse cluster")
+
+	lakehouseCleanupCmd.PersistentFlags().StringVar(&lcUser,
+		"user",
+		"default",
+		"User to connect to the ClickHouse cluster")
+
+	lakehouseCleanupCmd.PersistentFlags().StringVar(&lcPassword,
+		"password",
+		"",
+		"Password to connect to the ClickHouse cluster")
+
+	lakehouseCleanupCmd.PersistentFlags().StringVar(&lcRegion,
+		"region",
+		"",
+		"Region of the ClickHouse cluster")
+
+	lakehouseCleanupCmd.PersistentFlags().StringVar(&lcEnv,
+		"env",
+		"",
+		"Environment of the ClickHouse cluster")
+
+	lakehouseCleanupCmd.PersistentFlags().StringVar(&lcCloudProvider,
+		"cloud",
+		"",
+		"cloud provider of the Clickhouse cluster")
+
+	lakehouseCleanupCmd.PersistentFlags().StringVar(&lcSlackToken,
+		"slack-token",
+		os.Getenv("SLACK_TOKEN"),
+		"token to publish stats on Slack")
+
+	lakehouseCleanupCmd.PersistentFlags().IntVar(&lcMinUnusedDays,
+		"min-unused-days",
+		30,
+		"minimum number of consecutive days without a query before a table is considered stale")
+
+	lakehouseCleanupCmd.PersistentFlags().BoolVar(&lcDryRun,
+		"dry-run",
+		true,
+		"print the DROP TABLE statements instead of executing them")
+
+	lakehouseCleanupCmd.PersistentFlags().StringVar(&lcExcludeRegex,
+		"exclude-regex",
+		"",
+		"regular expression matching table names that must never be considered stale")
+
+	lakehouseCleanupCmd.PersistentFlags().StringVar(&lcProtectLabel,
+		"protect-label",
+		"",
+		"table comment value that exempts a table from cleanup, e.g. \"keep\"")
+}
+
+// runLakeHouseCleanupCmd is executed when the lakehouse-cleanup command is run.
+func runLakeHouseCleanupCmd(_ *cobra.Command, _ []string) error {
+	clickhouseAnalyticsURL, ok := providers.ClickHouseClusters[providers.LakeHouse][lcRegion]
+	if !ok {
+		return fmt.Errorf("%s is an invalid ClickHouse analytics region", lcRegion)
+	}
+	clickhouseAnalyticsURL = fmt.Sprintf(clickhouseAnalyticsURL, lcEnv)
+
+	creds := providers.ClickHouseCredentials{User: lcUser, Password: lcPassword}
+
+	var excludeRegex *regexp.Regexp
+	if lcExcludeRegex != "" {
+		compiled, err := regexp.Compile(lcExcludeRegex)
+		if err != nil {
+			return fmt.Errorf("invalid --exclude-regex: %w", err)
+		}
+		excludeRegex = compiled
+	}
+
+	staleTables, err := findStaleTables(clickhouseAnalyticsURL, creds, lcMinUnusedDays, excludeRegex, lcProtectLabel)
+	if err != nil {
+		return err
+	}
+
+	// Isolate errors per table (same pattern as runClusterTarget in
+	// lakehouseUsageMultiCluster.go): one failed drop must not stop the rest
+	// of the batch or swallow the Slack summary of what actually happened.
+	var reclaimedBytes int64
+	var dropped []StaleTable
+	var dropFailures []string
+	for _, staleTable := range staleTables {
+		dropStatement := fmt.Sprintf("DROP TABLE `%s`.`%s`", staleTable.Database, staleTable.Table)
+		if lcDryRun {
+			fmt.Println(dropStatement)
+			continue
+		}
+
+		queryStart := time.Now()
+		_, err := helpers.ExecuteClickHouseQuery[struct{}](dropStatement, clickhouseAnalyticsURL, creds)
+		metrics.ObserveQuery("lakehouse-cleanup", queryStart, err)
+		if err != nil {
+			dropFailures = append(dropFailures, fmt.Sprintf("%s.%s: %v", staleTable.Database, staleTable.Table, err))
+			continue
+		}
+		reclaimedBytes += staleTable.TotalBytes
+		dropped = append(dropped, staleTable)
+	}
+
+	summary := fmt.Sprintf("[%s][%s][%s] lakehouse-cleanup: %d stale table(s) found, %d dropped, %d failed, %.2f GB reclaimed (dry-run=%t)",
+		lcCloudProvider, lcRegion, lcEnv, len(staleTables), len(dropped), len(dropFailures), float64(reclaimedBytes)/(1<<30), lcDryRun)
+	fmt.Println(summary)
+
+	if err := slack.PublishMsgInThread(lcSlackToken, summary, cleanupDetails(staleTables, dropped, dropFailures, lcDryRun)); err != nil {
+		metrics.SlackPublishErrorsTotal.WithLabelValues("lakehouse-cleanup").Inc()
+		return err
+	}
+
+	if len(dropFailures) > 0 {
+		return fmt.Errorf("failed to drop %d table(s): %s", len(dropFailures), strings.Join(dropFailures, "; "))
+	}
+	return nil
+}
+
+// findStaleTables returns every table in the default database that has not
+// been queried in the last minUnusedDays days, skipping tables that match
+// excludeRegex or whose comment equals protectLabel. The query_log lookback
+// is bounded to the same minUnusedDays window, since a query outside it
+// cannot affect whether a table is considered stale.
+func findStaleTables(url string, creds providers.ClickHouseCredentials, minUnusedDays int, excludeRegex *regexp.Regexp, protectLabel string) ([]StaleTable, error) {
+	query := fmt.Sprintf(
+		"SELECT st.database AS database, "+
+			"st.name AS table, "+
+			"toString(max(ql.event_time)) AS last_used, "+
+			"toString(st.create_time) AS create_time, "+
+			"st.total_bytes AS total_bytes, "+
+			"st.comment AS comment "+
+			"FROM system.tables AS st "+
+			"LEFT JOIN (SELECT event_time, arrayJoin(tables) AS qt FROM system.query_log "+
+			"WHERE event_time >= now() - INTERVAL %d DAY) AS ql "+
+			"ON splitByChar('.', ql.qt)[2] = st.name "+
+			"WHERE st.database = 'default' "+
+			"AND st.create_time <= now() - INTERVAL %d DAY "+
+			"GROUP BY st.database, st.name, st.create_time, st.total_bytes, st.comment "+
+			"HAVING max(ql.event_time) IS NULL OR max(ql.event_time) <= now() - INTERVAL %d DAY",
+		minUnusedDays, minUnusedDays, minUnusedDays)
+
+	queryStart := time.Now()
+	resp, err := helpers.ExecuteClickHouseQuery[StaleTable](query, url, creds)
+	metrics.ObserveQuery("lakehouse-cleanup", queryStart, err)
+	if err != nil {
+		return nil, err
+	}
+
+	staleTables := make([]StaleTable, 0, len(resp.Data))
+	for _, row := range resp.Data {
+		if excludeRegex != nil && excludeRegex.MatchString(row.Table) {
+			continue
+		}
+		if protectLabel != "" && row.Comment == protectLabel {
+			continue
+		}
+		staleTables = append(staleTables, *row)
+	}
+	return staleTables, nil
+}
+
+// cleanupDetails formats the Slack thread reply. In dry-run mode it lists
+// every stale candidate found, since that is the only place an operator can
+// see what *would* be dropped; otherwise it lists the tables actually
+// dropped, followed by any drops that failed, so the reply stays the
+// authoritative record of what happened even when a batch is only partially
+// successful.
+func cleanupDetails(staleTables, dropped []StaleTable, dropFailures []string, dryRun bool) string {
+	tables := dropped
+	label := "Dropped tables"
+	if dryRun {
+		tables = staleTables
+		label = "Stale table candidates (dry-run, not dropped)"
+	}
+
+	details := "No stale tables found."
+	if len(tables) > 0 {
+		details = label + ":```\n"
+		for _, table := range tables {
+			details += fmt.Sprintf("%s.%s (%d bytes, last used %s)\n", table.Database, table.Table, table.TotalBytes, table.LastUsed)
+		}
+		details += "```"
+	}
+
+	if len(dropFailures) > 0 {
+		details += fmt.Sprintf("\nFailed to drop %d table(s):```\n%s```", len(dropFailures), strings.Join(dropFailures, "\n"))
+	}
+	return details
+}
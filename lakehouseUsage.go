@@ -3,10 +3,12 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"strings"
+	"time"
 
 	"github.com/contentsquare/lakehouse-cli/helpers"
+	"github.com/contentsquare/lakehouse-cli/metrics"
 	"github.com/contentsquare/lakehouse-cli/providers"
+	"github.com/contentsquare/lakehouse-cli/report"
 	"github.com/contentsquare/lakehouse-cli/slack"
 	"github.com/spf13/cobra"
 )
@@ -27,6 +29,12 @@ var (
 	lhEnv           string
 	lhCloudProvider string
 	lhSlackToken    string
+	lhAutoDiscover  bool
+	lhOutput        string
+	lhOutputFile    string
+	lhTop           int
+	lhConfigFile    string
+	lhSnapshotTable string
 )
 
 // Define the lakehouseUsageCmd command with its usage, short description, and execution function.
@@ -74,10 +82,44 @@ func init() {
 		"slack-token",
 		os.Getenv("SLACK_TOKEN"),
 		"token to publish stats on Slack")
+
+	lakehouseUsageCmd.PersistentFlags().BoolVar(&lhAutoDiscover,
+		"auto-discover",
+		false,
+		"discover every replica of the cluster via system.clusters and merge their query_log counts")
+
+	lakehouseUsageCmd.PersistentFlags().StringVar(&lhOutput,
+		"output",
+		"table",
+		"report format to render: table, json, or csv")
+
+	lakehouseUsageCmd.PersistentFlags().StringVar(&lhOutputFile,
+		"output-file",
+		"",
+		"path to write the json/csv report to; defaults to lakehouse-usage.<format> when --output is json or csv")
+
+	lakehouseUsageCmd.PersistentFlags().IntVar(&lhTop,
+		"top",
+		0,
+		"only keep the top N tables by query count, 0 means no truncation")
+
+	lakehouseUsageCmd.PersistentFlags().StringVar(&lhConfigFile,
+		"config",
+		"",
+		"path to a YAML file listing multiple cluster targets to run against in one invocation")
+
+	lakehouseUsageCmd.PersistentFlags().StringVar(&lhSnapshotTable,
+		"snapshot-table",
+		"lakehouse_cli.table_usage_daily",
+		"table to write one usage snapshot row per table per day into")
 }
 
 // The runLakeHouseUsageCmd function is executed when the lakehouse-usage command is run.
 func runLakeHouseUsageCmd(_ *cobra.Command, _ []string) error {
+	if lhConfigFile != "" {
+		return runMultiClusterUsageCmd(lhConfigFile)
+	}
+
 	// Get the ClickHouse Analytics URL based on the provided region
 	clickhouseAnalyticsURL, ok := providers.ClickHouseClusters[providers.LakeHouse][lhRegion]
 	if !ok {
@@ -88,6 +130,58 @@ func runLakeHouseUsageCmd(_ *cobra.Command, _ []string) error {
 	// Define credentials for connection to Clickhouse Cluster with  User and Password.
 	sourceCreds := providers.ClickHouseCredentials{User: lhUser, Password: lhPassword}
 
+	usedTables, unusedTables, err := fetchUsageData(clickhouseAnalyticsURL, sourceCreds, lhAutoDiscover)
+	if err != nil {
+		return err
+	}
+
+	// Writing the trend snapshot is best-effort: it must never stop the
+	// existing Slack usage report from being sent.
+	if err := writeUsageSnapshot(clickhouseAnalyticsURL, sourceCreds, lhSnapshotTable,
+		lhCloudProvider, lhRegion, lhEnv, usedTables, unusedTables); err != nil {
+		fmt.Printf("failed to write usage snapshot to %s: %v\n", lhSnapshotTable, err)
+	}
+
+	// Create a summary text for Slack.
+	lhSummaryText := fmt.Sprintf("[%s][%s][%s] Yesterday %d tables have been used on the Lakehouse and %d  not used.",
+		lhCloudProvider, lhRegion, lhEnv, len(usedTables), len(unusedTables))
+
+	doc := report.BuildDocument(usedTables, unusedTables, lhTop)
+
+	// Table output stays inline in the Slack thread; json/csv are uploaded as
+	// a file alongside the summary since they stop being readable as plain text.
+	if lhOutput == "table" {
+		lhSlackThreadMessage := fmt.Sprintf("Detailed data consistency:```\n%s```", report.RenderTable(doc))
+		fmt.Println(lhSlackThreadMessage)
+
+		if err := slack.PublishMsgInThread(lhSlackToken, lhSummaryText, lhSlackThreadMessage); err != nil {
+			metrics.SlackPublishErrorsTotal.WithLabelValues("lakehouse-usage").Inc()
+			return err
+		}
+		return nil
+	}
+
+	outputFile := lhOutputFile
+	if outputFile == "" {
+		outputFile = fmt.Sprintf("lakehouse-usage.%s", lhOutput)
+	}
+	if err := report.WriteFile(doc, lhOutput, outputFile); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %s report to %s\n", lhOutput, outputFile)
+
+	if err := slack.PublishFileInThread(lhSlackToken, lhSummaryText, outputFile); err != nil {
+		metrics.SlackPublishErrorsTotal.WithLabelValues("lakehouse-usage").Inc()
+		return err
+	}
+	return nil
+}
+
+// fetchUsageData runs the daily table-usage query against url (fanning out
+// to every replica first when autoDiscover is set) and returns the merged
+// used/unused tables, recording the lakehouse-usage query and gauge metrics
+// along the way.
+func fetchUsageData(url string, creds providers.ClickHouseCredentials, autoDiscover bool) (map[string]int, []string, error) {
 	// The number of queries successful per table for yesterday.
 	Query := "SELECT _table AS use_table_name, " +
 		"cnt, " +
@@ -110,38 +204,69 @@ func runLakeHouseUsageCmd(_ *cobra.Command, _ []string) error {
 		"ORDER BY used_tables.cnt DESC" +
 		BuildSettings(&Settings{true, true})
 
-	// Execute the query with the ExecuteClickHouseQuery function.
-	sourceResponse, err := helpers.ExecuteClickHouseQuery[Response](
-		Query,
-		clickhouseAnalyticsURL,
-		sourceCreds)
-	if err != nil {
-		return err
+	// By default we only query the single seed URL; with --auto-discover we
+	// fan out the same query to every replica of the cluster, since
+	// system.query_log is local to each node.
+	queryURLs := []string{url}
+	if autoDiscover {
+		replicas, discoverErr := providers.DiscoverReplicas(url, creds)
+		if discoverErr != nil {
+			return nil, nil, discoverErr
+		}
+		queryURLs = replicas
 	}
-	// Process the response data to separate used and unused tables.
-	usedTables, unusedTables := processResponseData(sourceResponse.Data)
-
-	// Create a summary text for Slack.
-	lhSummaryText := fmt.Sprintf("[%s][%s][%s] Yesterday %d tables have been used on the Lakehouse and %d  not used.",
-		lhCloudProvider, lhRegion, lhEnv, len(usedTables), len(unusedTables))
-	lhSlackThreadMessage := detailedMessage(usedTables, unusedTables)
-	fmt.Println(lhSlackThreadMessage)
 
-	// Publish message in Slack.
-	err = slack.PublishMsgInThread(lhSlackToken, lhSummaryText, lhSlackThreadMessage)
-	if err != nil {
-		return err
+	// Execute the query against every URL and merge the per-shard rows.
+	var perShardData [][]*Response
+	for _, queryURL := range queryURLs {
+		queryStart := time.Now()
+		shardResponse, err := helpers.ExecuteClickHouseQuery[Response](
+			Query,
+			queryURL,
+			creds)
+		metrics.ObserveQuery("lakehouse-usage", queryStart, err)
+		if err != nil {
+			return nil, nil, err
+		}
+		perShardData = append(perShardData, shardResponse.Data)
 	}
-	return nil
+
+	usedTables, unusedTables := processResponseData(mergeShardData(perShardData))
+	metrics.TablesUsed.WithLabelValues("lakehouse-usage").Set(float64(len(usedTables)))
+	metrics.TablesUnused.WithLabelValues("lakehouse-usage").Set(float64(len(unusedTables)))
+	return usedTables, unusedTables, nil
 }
 
-// Convert used tables map to string.
-func usedTablesToString(usedTables map[string]int) string {
-	var usedTablesStr string
-	for key, count := range usedTables {
-		usedTablesStr += fmt.Sprintf("%s\t%d\n", key, count)
+// mergeShardData combines the per-shard query_log counts produced by
+// --auto-discover into a single set of rows, summing the use counts of a
+// table across every shard/replica it was reported from. A table is only
+// considered unused if it came back unused on every shard.
+func mergeShardData(perShard [][]*Response) []*Response {
+	counts := make(map[string]int)
+	tables := make(map[string]struct{})
+	for _, shardData := range perShard {
+		for _, row := range shardData {
+			table := row.Table
+			if table == "" {
+				table = row.UnusedTable
+			}
+			tables[table] = struct{}{}
+			counts[table] += row.Count
+		}
 	}
-	return usedTablesStr
+
+	merged := make([]*Response, 0, len(tables))
+	for table := range tables {
+		count := counts[table]
+		row := &Response{Count: count}
+		if count > 0 {
+			row.Table = table
+		} else {
+			row.UnusedTable = table
+		}
+		merged = append(merged, row)
+	}
+	return merged
 }
 
 // Function to process response data and separate used and unused tables.
@@ -158,10 +283,3 @@ func processResponseData(resp []*Response) (map[string]int, []string) {
 	}
 	return usedTables, unusedTables
 }
-
-// detailedMessage generates a formatted message for Slack with details on used and unused tables.
-func detailedMessage(usedTables map[string]int, unusedTables []string) string {
-	detailedSlackThreadMessage := fmt.Sprintf("Detailed data consistency:```\nUsed Tables:\n%s\nUnused Tables:\n%s```",
-		usedTablesToString(usedTables), strings.Join(unusedTables, ","))
-	return detailedSlackThreadMessage
-}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/contentsquare/lakehouse-cli/metrics"
+	"github.com/contentsquare/lakehouse-cli/providers"
+	"github.com/contentsquare/lakehouse-cli/report"
+	"github.com/contentsquare/lakehouse-cli/slack"
+	"gopkg.in/yaml.v3"
+)
+
+// clusterTargetsConfig is the top-level shape of the --config YAML file.
+type clusterTargetsConfig struct {
+	Targets       []clusterTarget `yaml:"targets"`
+	Concurrency   int             `yaml:"concurrency"`
+	TargetTimeout time.Duration   `yaml:"targetTimeout"`
+}
+
+// clusterTarget identifies one (cloud, region, env) cluster to run the usage
+// query against, either with explicit connection details or a region that
+// resolves through providers.ClickHouseClusters.
+type clusterTarget struct {
+	Cloud        string `yaml:"cloud"`
+	Region       string `yaml:"region"`
+	Env          string `yaml:"env"`
+	URL          string `yaml:"url"`
+	User         string `yaml:"user"`
+	Password     string `yaml:"password"`
+	SecretRef    string `yaml:"secretRef"`
+	AutoDiscover bool   `yaml:"autoDiscover"`
+}
+
+// targetResult holds the outcome of running the usage query against a single
+// clusterTarget, for consolidation into one Slack digest.
+type targetResult struct {
+	target       clusterTarget
+	usedTables   map[string]int
+	unusedTables []string
+	err          error
+}
+
+// runMultiClusterUsageCmd loads the targets listed in configPath, runs the
+// usage query against each of them concurrently, and posts a single
+// consolidated Slack thread: the parent message is the cross-cluster
+// summary, and each reply is one cluster's detailed report. One failing
+// target never aborts the others; the command only returns an error if every
+// target failed.
+func runMultiClusterUsageCmd(configPath string) error {
+	config, err := loadClusterTargetsConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	concurrency := config.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+	targetTimeout := config.TargetTimeout
+	if targetTimeout <= 0 {
+		targetTimeout = 2 * time.Minute
+	}
+
+	results := make([]targetResult, len(config.Targets))
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, target := range config.Targets {
+		wg.Add(1)
+		go func(i int, target clusterTarget) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			results[i] = runClusterTarget(target, targetTimeout)
+		}(i, target)
+	}
+	wg.Wait()
+
+	return publishMultiClusterDigest(results)
+}
+
+// runClusterTarget resolves credentials/URL for target, enforces timeout,
+// and fetches its usage data.
+func runClusterTarget(target clusterTarget, timeout time.Duration) targetResult {
+	url := target.URL
+	if url == "" {
+		clusterURL, ok := providers.ClickHouseClusters[providers.LakeHouse][target.Region]
+		if !ok {
+			return targetResult{target: target, err: fmt.Errorf("%s is an invalid ClickHouse analytics region", target.Region)}
+		}
+		url = fmt.Sprintf(clusterURL, target.Env)
+	}
+
+	password := target.Password
+	if password == "" && target.SecretRef != "" {
+		resolved, err := resolveSecretRef(target.SecretRef)
+		if err != nil {
+			return targetResult{target: target, err: err}
+		}
+		password = resolved
+	}
+	creds := providers.ClickHouseCredentials{User: target.User, Password: password}
+
+	done := make(chan targetResult, 1)
+	go func() {
+		usedTables, unusedTables, err := fetchUsageData(url, creds, target.AutoDiscover)
+		if err == nil {
+			// Best-effort, same as the single-cluster path: a snapshot failure
+			// must not hide this target's usage result from the digest.
+			if snapshotErr := writeUsageSnapshot(url, creds, lhSnapshotTable,
+				target.Cloud, target.Region, target.Env, usedTables, unusedTables); snapshotErr != nil {
+				fmt.Printf("failed to write usage snapshot for [%s][%s][%s]: %v\n", target.Cloud, target.Region, target.Env, snapshotErr)
+			}
+		}
+		done <- targetResult{target: target, usedTables: usedTables, unusedTables: unusedTables, err: err}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	select {
+	case result := <-done:
+		return result
+	case <-ctx.Done():
+		return targetResult{target: target, err: fmt.Errorf("timed out after %s", timeout)}
+	}
+}
+
+// publishMultiClusterDigest posts the cross-cluster summary as a Slack
+// parent message and one reply per target, isolating errors so that a
+// single bad target is reported but does not hide the others' results.
+func publishMultiClusterDigest(results []targetResult) error {
+	var totalUsed, totalUnused, failures int
+	for _, result := range results {
+		if result.err != nil {
+			failures++
+			continue
+		}
+		totalUsed += len(result.usedTables)
+		totalUnused += len(result.unusedTables)
+	}
+
+	summary := fmt.Sprintf("Lakehouse usage digest: %d cluster(s), %d failed, %d tables used, %d tables unused overall.",
+		len(results), failures, totalUsed, totalUnused)
+	fmt.Println(summary)
+
+	replies := make([]string, len(results))
+	for i, result := range results {
+		replies[i] = targetReply(result)
+		fmt.Println(replies[i])
+	}
+
+	if err := slack.PublishRepliesInThread(lhSlackToken, summary, replies); err != nil {
+		metrics.SlackPublishErrorsTotal.WithLabelValues("lakehouse-usage").Inc()
+		return err
+	}
+
+	if failures == len(results) {
+		return fmt.Errorf("all %d cluster target(s) failed", failures)
+	}
+	return nil
+}
+
+// targetReply formats one cluster's result as a Slack thread reply.
+func targetReply(result targetResult) string {
+	prefix := fmt.Sprintf("[%s][%s][%s]", result.target.Cloud, result.target.Region, result.target.Env)
+	if result.err != nil {
+		return fmt.Sprintf("%s failed: %v", prefix, result.err)
+	}
+	doc := report.BuildDocument(result.usedTables, result.unusedTables, 0)
+	return fmt.Sprintf("%s Yesterday %d tables have been used and %d not used.```\n%s```",
+		prefix, len(result.usedTables), len(result.unusedTables), report.RenderTable(doc))
+}
+
+// loadClusterTargetsConfig reads and parses the --config YAML file.
+func loadClusterTargetsConfig(path string) (clusterTargetsConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return clusterTargetsConfig{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var config clusterTargetsConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return clusterTargetsConfig{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(config.Targets) == 0 {
+		return clusterTargetsConfig{}, fmt.Errorf("%s lists no targets", path)
+	}
+	return config, nil
+}
+
+// resolveSecretRef resolves a secretRef entry to its plaintext value. Refs
+// are expected in the form "env:VAR_NAME", matching how the slack-token flag
+// already falls back to the SLACK_TOKEN environment variable.
+func resolveSecretRef(ref string) (string, error) {
+	const envPrefix = "env:"
+	if len(ref) > len(envPrefix) && ref[:len(envPrefix)] == envPrefix {
+		return os.Getenv(ref[len(envPrefix):]), nil
+	}
+	return "", fmt.Errorf("unsupported secretRef %q, expected \"env:VAR_NAME\"", ref)
+}
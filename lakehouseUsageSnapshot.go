@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/contentsquare/lakehouse-cli/helpers"
+	"github.com/contentsquare/lakehouse-cli/metrics"
+	"github.com/contentsquare/lakehouse-cli/providers"
+)
+
+// writeUsageSnapshot inserts one row per table per day into snapshotTable,
+// so lakehouse-usage-trend has a longitudinal view to work from instead of
+// a single day's report.
+func writeUsageSnapshot(url string, creds providers.ClickHouseCredentials, snapshotTable, cloud, region, env string,
+	usedTables map[string]int, unusedTables []string) error {
+	if len(usedTables) == 0 && len(unusedTables) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(usedTables)+len(unusedTables))
+	for table, count := range usedTables {
+		values = append(values, snapshotRowValues(cloud, region, env, table, count, true))
+	}
+	for _, table := range unusedTables {
+		values = append(values, snapshotRowValues(cloud, region, env, table, 0, false))
+	}
+
+	insertQuery := fmt.Sprintf(
+		"INSERT INTO %s (event_date, cloud, region, env, database, table, query_count, is_used) VALUES %s",
+		snapshotTable, strings.Join(values, ", "))
+
+	queryStart := time.Now()
+	_, err := helpers.ExecuteClickHouseQuery[struct{}](insertQuery, url, creds)
+	metrics.ObserveQuery("lakehouse-usage-snapshot", queryStart, err)
+	if err != nil {
+		return fmt.Errorf("failed to write usage snapshot to %s: %w", snapshotTable, err)
+	}
+	return nil
+}
+
+// snapshotRowValues renders a single VALUES tuple for the snapshot table.
+func snapshotRowValues(cloud, region, env, table string, count int, isUsed bool) string {
+	return fmt.Sprintf("(yesterday(), '%s', '%s', '%s', 'default', '%s', %d, %t)",
+		escapeSQLString(cloud), escapeSQLString(region), escapeSQLString(env), escapeSQLString(table), count, isUsed)
+}
+
+// escapeSQLString escapes backslashes and single quotes so a value can be
+// safely embedded in a single-quoted ClickHouse SQL string literal.
+func escapeSQLString(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "'", "''").Replace(value)
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/contentsquare/lakehouse-cli/helpers"
+	"github.com/contentsquare/lakehouse-cli/metrics"
+	"github.com/contentsquare/lakehouse-cli/providers"
+	"github.com/contentsquare/lakehouse-cli/slack"
+	"github.com/spf13/cobra"
+)
+
+// TrendRow is a table whose usage has either dropped sharply week-over-week
+// or has been unused for several consecutive days, as read back from the
+// usage snapshot table.
+type TrendRow struct {
+	Database      string  `json:"database"`
+	Table         string  `json:"table"`
+	ThisWeekCount int     `json:"this_week_count"`
+	LastWeekCount int     `json:"last_week_count"`
+	DropPercent   float64 `json:"drop_percent"`
+	UnusedDays    int     `json:"unused_days"`
+}
+
+// Variables to store flag values.
+var (
+	ltURL           string
+	ltUser          string
+	ltPassword      string
+	ltRegion        string
+	ltEnv           string
+	ltCloudProvider string
+	ltSlackToken    string
+	ltSnapshotTable string
+	ltDropThreshold float64
+	ltUnusedDays    int
+)
+
+// Define the lakehouseUsageTrendCmd command with its usage, short description, and execution function.
+var lakehouseUsageTrendCmd = &cobra.Command{
+	Use:   "lakehouse-usage-trend",
+	Short: "Reports tables whose query volume dropped sharply or that have been unused for several days in a row",
+	RunE:  runLakeHouseUsageTrendCmd,
+}
+
+// init function to initialize the command and its flags.
+func init() {
+	rootCmd.AddCommand(lakehouseUsageTrendCmd)
+
+	lakehouseUsageTrendCmd.PersistentFlags().StringVar(&ltURL,
+		"url",
+		"",
+		"URL of the ClickHouse cluster")
+
+	lakehouseUsageTrendCmd.PersistentFlags().StringVar(&ltUser,
+		"user",
+		"default",
+		"User to connect to the ClickHouse cluster")
+
+	lakehouseUsageTrendCmd.PersistentFlags().StringVar(&ltPassword,
+		"password",
+		"",
+		"Password to connect to the ClickHouse cluster")
+
+	lakehouseUsageTrendCmd.PersistentFlags().StringVar(&ltRegion,
+		"region",
+		"",
+		"Region of the ClickHouse cluster")
+
+	lakehouseUsageTrendCmd.PersistentFlags().StringVar(&ltEnv,
+		"env",
+		"",
+		"Environment of the ClickHouse cluster")
+
+	lakehouseUsageTrendCmd.PersistentFlags().StringVar(&ltCloudProvider,
+		"cloud",
+		"",
+		"cloud provider of the Clickhouse cluster")
+
+	lakehouseUsageTrendCmd.PersistentFlags().StringVar(&ltSlackToken,
+		"slack-token",
+		os.Getenv("SLACK_TOKEN"),
+		"token to publish stats on Slack")
+
+	lakehouseUsageTrendCmd.PersistentFlags().StringVar(&ltSnapshotTable,
+		"snapshot-table",
+		"lakehouse_cli.table_usage_daily",
+		"snapshot table written by lakehouse-usage to read trends from")
+
+	lakehouseUsageTrendCmd.PersistentFlags().Float64Var(&ltDropThreshold,
+		"drop-threshold-percent",
+		50,
+		"report tables whose week-over-week query count dropped by at least this percentage")
+
+	lakehouseUsageTrendCmd.PersistentFlags().IntVar(&ltUnusedDays,
+		"unused-days",
+		7,
+		"report tables that have been unused for at least this many consecutive days")
+}
+
+// runLakeHouseUsageTrendCmd is executed when the lakehouse-usage-trend command is run.
+func runLakeHouseUsageTrendCmd(_ *cobra.Command, _ []string) error {
+	clickhouseAnalyticsURL, ok := providers.ClickHouseClusters[providers.LakeHouse][ltRegion]
+	if !ok {
+		return fmt.Errorf("%s is an invalid ClickHouse analytics region", ltRegion)
+	}
+	clickhouseAnalyticsURL = fmt.Sprintf(clickhouseAnalyticsURL, ltEnv)
+
+	creds := providers.ClickHouseCredentials{User: ltUser, Password: ltPassword}
+
+	trendRows, err := findTrendingTables(clickhouseAnalyticsURL, creds, ltSnapshotTable, ltDropThreshold, ltUnusedDays)
+	if err != nil {
+		return err
+	}
+
+	summary := fmt.Sprintf("[%s][%s][%s] lakehouse-usage-trend: %d table(s) dropped >=%.0f%% week-over-week or unused for %d+ consecutive days.",
+		ltCloudProvider, ltRegion, ltEnv, len(trendRows), ltDropThreshold, ltUnusedDays)
+	fmt.Println(summary)
+
+	if err := slack.PublishMsgInThread(ltSlackToken, summary, trendDetails(trendRows)); err != nil {
+		metrics.SlackPublishErrorsTotal.WithLabelValues("lakehouse-usage-trend").Inc()
+		return err
+	}
+	return nil
+}
+
+// findTrendingTables reads snapshotTable and returns tables whose
+// week-over-week query count dropped by at least dropThreshold percent, or
+// that have been unused for at least unusedDays *consecutive* days counting
+// back from the most recent snapshot. unused_days is computed from an array
+// of each table's is_used flags ordered most-recent-first: it is the number
+// of leading zeros before the first "used" day (or the full window length
+// if the table was never used in it), not just a raw count of unused days.
+func findTrendingTables(url string, creds providers.ClickHouseCredentials, snapshotTable string, dropThreshold float64, unusedDays int) ([]*TrendRow, error) {
+	const weekOverWeekWindow = 14
+	lookbackDays := unusedDays
+	if lookbackDays < weekOverWeekWindow {
+		lookbackDays = weekOverWeekWindow
+	}
+
+	query := fmt.Sprintf(
+		"SELECT database, table, this_week_count, last_week_count, drop_percent, unused_days "+
+			"FROM ( "+
+			"SELECT database, table, this_week_count, last_week_count, "+
+			"if(last_week_count = 0, 0, 100.0 * (1 - this_week_count / last_week_count)) AS drop_percent, "+
+			"if(arrayFirstIndex(x -> x = 1, usage_desc) = 0, "+
+			"length(usage_desc), "+
+			"arrayFirstIndex(x -> x = 1, usage_desc) - 1) AS unused_days "+
+			"FROM ( "+
+			"SELECT database, table, "+
+			"sum(if(event_date >= today() - 7, query_count, 0)) AS this_week_count, "+
+			"sum(if(event_date >= today() - 14 AND event_date < today() - 7, query_count, 0)) AS last_week_count, "+
+			"arrayMap(t -> t.2, arrayReverseSort(t -> t.1, groupArray((event_date, is_used)))) AS usage_desc "+
+			"FROM %s "+
+			"WHERE event_date >= today() - %d "+
+			"GROUP BY database, table "+
+			") "+
+			") "+
+			"WHERE drop_percent >= %f OR unused_days >= %d "+
+			"ORDER BY drop_percent DESC",
+		snapshotTable, lookbackDays, dropThreshold, unusedDays)
+
+	queryStart := time.Now()
+	resp, err := helpers.ExecuteClickHouseQuery[TrendRow](query, url, creds)
+	metrics.ObserveQuery("lakehouse-usage-trend", queryStart, err)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// trendDetails formats the trending tables for the Slack thread reply.
+func trendDetails(rows []*TrendRow) string {
+	if len(rows) == 0 {
+		return "No trending tables found."
+	}
+	details := "Trending tables:```\n"
+	for _, row := range rows {
+		details += fmt.Sprintf("%s.%s: this week=%d, last week=%d, drop=%.1f%%, unused_days=%d\n",
+			row.Database, row.Table, row.ThisWeekCount, row.LastWeekCount, row.DropPercent, row.UnusedDays)
+	}
+	details += "```"
+	return details
+}
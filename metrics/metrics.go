@@ -0,0 +1,98 @@
+// Package metrics provides Prometheus instrumentation for lakehouse-cli
+// commands, mirroring the ingester metrics pattern used in klogs.
+package metrics
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// QueryTotal counts every ClickHouse query executed through helpers.ExecuteClickHouseQuery, labelled by command.
+var QueryTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "lakehouse_cli_query_total",
+	Help: "Total number of ClickHouse queries executed.",
+}, []string{"command"})
+
+// QueryErrorsTotal counts ClickHouse queries that returned an error, labelled by command.
+var QueryErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "lakehouse_cli_query_errors_total",
+	Help: "Total number of ClickHouse queries that returned an error.",
+}, []string{"command"})
+
+// QueryDurationSeconds observes the wall-clock duration of ClickHouse queries, labelled by command.
+var QueryDurationSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "lakehouse_cli_query_duration_seconds",
+	Help:    "Duration of ClickHouse queries in seconds.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"command"})
+
+// TablesUsed reports the number of tables found used in the last run, labelled by command.
+var TablesUsed = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "lakehouse_cli_tables_used",
+	Help: "Number of tables found used in the last run.",
+}, []string{"command"})
+
+// TablesUnused reports the number of tables found unused in the last run, labelled by command.
+var TablesUnused = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "lakehouse_cli_tables_unused",
+	Help: "Number of tables found unused in the last run.",
+}, []string{"command"})
+
+// SlackPublishErrorsTotal counts failures to publish a message or file to Slack, labelled by command.
+var SlackPublishErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "lakehouse_cli_slack_publish_errors_total",
+	Help: "Total number of Slack publish failures.",
+}, []string{"command"})
+
+// ObserveQuery instruments a single ClickHouse query execution, recording the
+// outcome and duration under the given command label. Call it with the
+// result of time.Now() taken immediately before the query ran.
+func ObserveQuery(command string, start time.Time, err error) {
+	QueryTotal.WithLabelValues(command).Inc()
+	QueryDurationSeconds.WithLabelValues(command).Observe(time.Since(start).Seconds())
+	if err != nil {
+		QueryErrorsTotal.WithLabelValues(command).Inc()
+	}
+}
+
+// StartServer starts an HTTP server exposing the /metrics endpoint on addr.
+// It runs in the background and logs a fatal-free error to stderr if the
+// listener cannot be started; callers that need a one-shot cron run should
+// use PushToGateway instead.
+func StartServer(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Printf("metrics: failed to start server on %s: %v", addr, err)
+		}
+	}()
+}
+
+// PushToGateway pushes the default Prometheus registry to the given push
+// gateway URL under the given job name. It is intended to be called once,
+// right before a one-shot command exits, since a cron-triggered CLI run
+// never lives long enough for a scrape to find it.
+func PushToGateway(gatewayURL, job string) error {
+	if gatewayURL == "" {
+		return nil
+	}
+	if err := push.New(gatewayURL, job).
+		Collector(QueryTotal).
+		Collector(QueryErrorsTotal).
+		Collector(QueryDurationSeconds).
+		Collector(TablesUsed).
+		Collector(TablesUnused).
+		Collector(SlackPublishErrorsTotal).
+		Push(); err != nil {
+		return fmt.Errorf("failed to push metrics to %s: %w", gatewayURL, err)
+	}
+	return nil
+}
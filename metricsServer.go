@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"github.com/contentsquare/lakehouse-cli/metrics"
+	"github.com/spf13/cobra"
+)
+
+// Variables to store flag values.
+var (
+	metricsServerAddress string
+	pushGatewayURL       string
+)
+
+// init function to register the metrics-related persistent flags on the root
+// command, start the metrics server before every command run, and push
+// metrics after it. The push runs from PersistentPostRun, which cobra skips
+// when a command's RunE returns an error, so failed runs are not pushed.
+func init() {
+	rootCmd.PersistentFlags().StringVar(&metricsServerAddress,
+		"metrics-server.address",
+		":2021",
+		"address to expose the Prometheus /metrics endpoint on, set to \"\" to disable")
+
+	rootCmd.PersistentFlags().StringVar(&pushGatewayURL,
+		"push-gateway",
+		"",
+		"Prometheus push gateway URL to push metrics to before exiting, for one-shot cron runs")
+
+	rootCmd.PersistentPreRun = func(cmd *cobra.Command, _ []string) {
+		if metricsServerAddress != "" {
+			metrics.StartServer(metricsServerAddress)
+		}
+	}
+
+	rootCmd.PersistentPostRun = func(cmd *cobra.Command, _ []string) {
+		_ = metrics.PushToGateway(pushGatewayURL, cmd.Name())
+	}
+}
@@ -0,0 +1,81 @@
+package providers
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/contentsquare/lakehouse-cli/helpers"
+	"github.com/contentsquare/lakehouse-cli/metrics"
+)
+
+// clusterRow defines the shape of a row returned by a system.clusters query.
+type clusterRow struct {
+	HostName string `json:"host_name"`
+	Cluster  string `json:"cluster"`
+}
+
+// DiscoverReplicas queries system.clusters on the seed host behind seedURL
+// and returns one ClickHouse HTTP URL per shard/replica of the cluster the
+// seed host itself belongs to, reusing the scheme and port of seedURL. A
+// seed node's config commonly defines more than one named cluster, so rows
+// are first narrowed down to the cluster whose membership includes the
+// seed's own host_name before being turned into URLs; otherwise the usage
+// query would fan out to unrelated clusters sharing the same seed node. The
+// port column in system.clusters is the inter-server/native protocol port
+// (used by the Distributed engine), not the HTTP port this CLI talks to, so
+// it is never trusted for the replica URLs; every replica is assumed to
+// serve HTTP on the same port as the seed. query_log is local to each node,
+// so callers that need cluster-wide counts must fan out the same query to
+// every URL returned here and merge the results themselves.
+func DiscoverReplicas(seedURL string, creds ClickHouseCredentials) ([]string, error) {
+	const query = "SELECT host_name, cluster FROM system.clusters"
+
+	queryStart := time.Now()
+	resp, err := helpers.ExecuteClickHouseQuery[clusterRow](query, seedURL, creds)
+	metrics.ObserveQuery("discover-replicas", queryStart, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover replicas from %s: %w", seedURL, err)
+	}
+
+	parsed, err := url.Parse(seedURL)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("%s is not a valid ClickHouse URL", seedURL)
+	}
+
+	hostPort := parsed.Port()
+	seedHost := parsed.Hostname()
+
+	var seedCluster string
+	for _, row := range resp.Data {
+		if row.HostName == seedHost {
+			seedCluster = row.Cluster
+			break
+		}
+	}
+	if seedCluster == "" {
+		return nil, fmt.Errorf("%s (host %s) is not a member of any cluster in system.clusters", seedURL, seedHost)
+	}
+
+	seen := make(map[string]struct{}, len(resp.Data))
+	replicas := make([]string, 0, len(resp.Data))
+	for _, row := range resp.Data {
+		if row.Cluster != seedCluster {
+			continue
+		}
+		replicaURL := fmt.Sprintf("%s://%s", parsed.Scheme, row.HostName)
+		if hostPort != "" {
+			replicaURL = fmt.Sprintf("%s:%s", replicaURL, hostPort)
+		}
+		if _, ok := seen[replicaURL]; ok {
+			continue
+		}
+		seen[replicaURL] = struct{}{}
+		replicas = append(replicas, replicaURL)
+	}
+
+	if len(replicas) == 0 {
+		return nil, fmt.Errorf("no replicas found for cluster %q via %s", seedCluster, seedURL)
+	}
+	return replicas, nil
+}
@@ -0,0 +1,115 @@
+// Package report renders the lakehouse usage data collected by the cmd
+// package as an ASCII table, JSON document, or CSV file.
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// Row is a single used/unused table entry ready to be rendered.
+type Row struct {
+	Table string `json:"table"`
+	Count int    `json:"count"`
+	Used  bool   `json:"used"`
+}
+
+// Document is the full report handed to a renderer: the rows to display and
+// how many additional rows were truncated by --top.
+type Document struct {
+	Rows      []Row `json:"rows"`
+	Truncated int   `json:"truncated"`
+}
+
+// BuildDocument turns the used/unused maps produced by processResponseData
+// into a sorted Document, keeping only the top N rows by count when top > 0.
+func BuildDocument(usedTables map[string]int, unusedTables []string, top int) Document {
+	rows := make([]Row, 0, len(usedTables)+len(unusedTables))
+	for table, count := range usedTables {
+		rows = append(rows, Row{Table: table, Count: count, Used: true})
+	}
+	for _, table := range unusedTables {
+		rows = append(rows, Row{Table: table, Count: 0, Used: false})
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Count != rows[j].Count {
+			return rows[i].Count > rows[j].Count
+		}
+		return rows[i].Table < rows[j].Table
+	})
+
+	truncated := 0
+	if top > 0 && len(rows) > top {
+		truncated = len(rows) - top
+		rows = rows[:top]
+	}
+	return Document{Rows: rows, Truncated: truncated}
+}
+
+// RenderTable renders doc as a bordered ASCII table suitable for a Slack
+// code block. When rows were truncated, a "...and X more" footer row is
+// appended.
+func RenderTable(doc Document) string {
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"Table", "Count", "Used"})
+	for _, row := range doc.Rows {
+		t.AppendRow(table.Row{row.Table, row.Count, row.Used})
+	}
+	if doc.Truncated > 0 {
+		t.AppendFooter(table.Row{fmt.Sprintf("...and %d more", doc.Truncated), "", ""})
+	}
+	return t.Render()
+}
+
+// RenderJSON renders doc as a pretty-printed JSON document.
+func RenderJSON(doc Document) ([]byte, error) {
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// RenderCSV renders doc as CSV, with a header row and one row per table.
+func RenderCSV(doc Document) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write([]string{"table", "count", "used"}); err != nil {
+		return nil, err
+	}
+	for _, row := range doc.Rows {
+		if err := w.Write([]string{row.Table, strconv.Itoa(row.Count), strconv.FormatBool(row.Used)}); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteFile renders doc in the given format ("table", "json", or "csv") and
+// writes it to path, returning an error for an unsupported format.
+func WriteFile(doc Document, format, path string) error {
+	var content []byte
+	var err error
+	switch format {
+	case "json":
+		content, err = RenderJSON(doc)
+	case "csv":
+		content, err = RenderCSV(doc)
+	case "table":
+		content = []byte(RenderTable(doc))
+	default:
+		return fmt.Errorf("unsupported output format %q", format)
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0o644)
+}
@@ -0,0 +1,68 @@
+package slack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/slack-go/slack"
+)
+
+// PublishFileInThread posts summary as the parent message of a new thread,
+// like PublishMsgInThread, then uploads the file at path as a reply in that
+// thread. It is used for report formats (json, csv) that are no longer
+// readable once pasted inline.
+func PublishFileInThread(token, summary, path string) error {
+	client := slack.New(token)
+
+	channel, threadTimestamp, err := postSummary(client, summary)
+	if err != nil {
+		return fmt.Errorf("failed to post summary before file upload: %w", err)
+	}
+
+	_, err = client.UploadFileV2(slack.UploadFileV2Parameters{
+		Channel:         channel,
+		File:            path,
+		Filename:        filepath.Base(path),
+		ThreadTimestamp: threadTimestamp,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", path, err)
+	}
+	return nil
+}
+
+// PublishRepliesInThread posts summary as the parent message of a new
+// thread, then posts each of replies as its own message in that thread. It
+// is used when the detail naturally splits into independent chunks (e.g.
+// one per cluster) that should stay individually readable and under
+// Slack's per-message size limit, instead of being concatenated into one
+// oversized reply.
+func PublishRepliesInThread(token, summary string, replies []string) error {
+	client := slack.New(token)
+
+	channel, threadTimestamp, err := postSummary(client, summary)
+	if err != nil {
+		return fmt.Errorf("failed to post summary before replies: %w", err)
+	}
+
+	for _, reply := range replies {
+		if _, _, err := client.PostMessage(channel,
+			slack.MsgOptionText(reply, false),
+			slack.MsgOptionTS(threadTimestamp)); err != nil {
+			return fmt.Errorf("failed to post thread reply: %w", err)
+		}
+	}
+	return nil
+}
+
+// postSummary posts summary to the configured channel and returns the
+// channel ID and message timestamp so a reply can be threaded under it.
+func postSummary(client *slack.Client, summary string) (string, string, error) {
+	channel := os.Getenv("SLACK_CHANNEL")
+	channelID, timestamp, err := client.PostMessage(channel, slack.MsgOptionText(summary, false))
+	if err != nil {
+		return "", "", err
+	}
+	return channelID, timestamp, nil
+}